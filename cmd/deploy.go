@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/devspace-cloud/devspace/cmd/flags"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/configutil"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/deploy/helm"
+	"github.com/devspace-cloud/devspace/pkg/devspace/deploy/plugin"
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// DeployCmd holds the deploy cmd flags
+type DeployCmd struct {
+	*flags.GlobalFlags
+}
+
+// NewDeployCmd creates a new deploy command
+func NewDeployCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DeployCmd{GlobalFlags: globalFlags}
+
+	deployCmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploys the current project",
+		Long: `
+#######################################################
+################## devspace deploy ####################
+#######################################################
+Deploys every deployment in devspace.yaml to the cluster
+and context resolved from --kubeconfig, --context,
+--namespace and $KUBECONFIG, exactly the way kubectl and
+helm themselves resolve cluster access.
+#######################################################
+	`,
+		RunE: cmd.RunDeploy,
+	}
+
+	return deployCmd
+}
+
+// RunDeploy executes the functionality "devspace deploy"
+func (cmd *DeployCmd) RunDeploy(cobraCmd *cobra.Command, args []string) error {
+	configExists, err := configutil.SetDevSpaceRoot(log.GetInstance())
+	if err != nil {
+		return err
+	}
+	if !configExists {
+		return errors.New("Couldn't find a DevSpace configuration. Please run `devspace init`")
+	}
+
+	options := &configutil.ConfigOptions{
+		Profile:         cmd.Profile,
+		Vars:            cmd.Vars,
+		KubeConfigFlags: cmd.ConfigFlags,
+	}
+
+	config, err := configutil.GetConfig(options)
+	if err != nil {
+		return err
+	}
+
+	kubeClient, err := kubectl.NewClientFromGetter(configutil.RESTClientGetter(options))
+	if err != nil {
+		return errors.Wrap(err, "create kube client")
+	}
+
+	generatedConfig, err := generated.LoadConfig(options.Profile)
+	if err != nil {
+		return err
+	}
+
+	cache, ok := generatedConfig.Profiles[generatedConfig.ActiveProfile]
+	if !ok {
+		cache = &generated.CacheConfig{Deployments: map[string]*generated.DeploymentCache{}}
+		generatedConfig.Profiles[generatedConfig.ActiveProfile] = cache
+	}
+
+	for _, deployConfig := range config.Deployments {
+		err = deployOne(cobraCmd.Context(), deployConfig, config, kubeClient, cache)
+		// Persist whatever cache state this deployment produced even if it (or
+		// a later one) failed, so a retry doesn't redeploy work that already
+		// succeeded
+		saveErr := generated.SaveConfig(generatedConfig)
+		if err != nil {
+			return errors.Wrapf(err, "deploy %s", deployConfig.Name)
+		}
+		if saveErr != nil {
+			return saveErr
+		}
+
+		log.GetInstance().Done("Deployed " + deployConfig.Name)
+	}
+
+	return nil
+}
+
+// deployOne dispatches deployConfig to whichever backend it names: helm,
+// plain kubectl manifests, or a plugin-provided custom backend
+func deployOne(ctx context.Context, deployConfig *latest.DeploymentConfig, config *latest.Config, kubeClient *kubectl.Client, cache *generated.CacheConfig) error {
+	switch {
+	case deployConfig.Helm != nil:
+		deployClient, err := helm.New(config, kubeClient, deployConfig, log.GetInstance())
+		if err != nil {
+			return err
+		}
+
+		return deployClient.Deploy(cache)
+
+	case deployConfig.Kubectl != nil:
+		for _, manifestPath := range deployConfig.Kubectl.Manifests {
+			manifest, err := ioutil.ReadFile(manifestPath)
+			if err != nil {
+				return errors.Wrapf(err, "read manifest %s", manifestPath)
+			}
+
+			err = kubeClient.ApplyManifests(manifest, kubeClient.Namespace)
+			if err != nil {
+				return errors.Wrapf(err, "apply manifest %s", manifestPath)
+			}
+		}
+
+		return nil
+
+	case deployConfig.Custom != nil:
+		for backendName := range deployConfig.Custom {
+			backend, ok := plugin.Get(backendName)
+			if !ok {
+				return errors.Errorf("no plugin registered for backend '%s'", backendName)
+			}
+
+			err := backend.Deploy(ctx, deployConfig, kubeClient, log.GetInstance())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return errors.Errorf("deployment %s specifies neither helm, kubectl nor custom", deployConfig.Name)
+}
@@ -0,0 +1,31 @@
+package flags
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// GlobalFlags holds the flags that are shared by every devspace command
+// which talks to a kubernetes cluster
+type GlobalFlags struct {
+	// ConfigFlags mirrors kubectl's own --kubeconfig/--context/--namespace/
+	// --as/--as-group flags so devspace resolves cluster access identically
+	ConfigFlags *genericclioptions.ConfigFlags
+
+	Profile string
+	Vars    []string
+}
+
+// NewGlobalFlags creates a new GlobalFlags instance and binds it to cobraCmd's
+// persistent flags
+func NewGlobalFlags(cobraCmd *cobra.Command) *GlobalFlags {
+	globalFlags := &GlobalFlags{
+		ConfigFlags: genericclioptions.NewConfigFlags(false),
+	}
+
+	globalFlags.ConfigFlags.AddFlags(cobraCmd.PersistentFlags())
+	cobraCmd.PersistentFlags().StringVar(&globalFlags.Profile, "profile", "", "The devspace profile to use")
+	cobraCmd.PersistentFlags().StringArrayVar(&globalFlags.Vars, "var", []string{}, "Variables to override during config parsing (key=value)")
+
+	return globalFlags
+}
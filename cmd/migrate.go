@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/devspace-cloud/devspace/pkg/util/paths"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// MigrateCmd holds the migrate cmd flags
+type MigrateCmd struct{}
+
+// NewMigrateCmd creates a new migrate command
+func NewMigrateCmd() *cobra.Command {
+	cmd := &MigrateCmd{}
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrates devspace state between on-disk layouts",
+	}
+
+	migrateCmd.AddCommand(&cobra.Command{
+		Use:   "xdg",
+		Short: "Moves ~/.devspace into the XDG Base Directory locations",
+		Long: `
+#######################################################
+################# devspace migrate xdg ################
+#######################################################
+Moves the legacy ~/.devspace directory into
+$XDG_CONFIG_HOME, $XDG_CACHE_HOME and $XDG_DATA_HOME,
+so future devspace commands pick up the XDG locations
+instead of falling back to ~/.devspace.
+#######################################################
+	`,
+		RunE: cmd.RunMigrateXDG,
+	})
+
+	return migrateCmd
+}
+
+// RunMigrateXDG executes the functionality "devspace migrate xdg"
+func (cmd *MigrateCmd) RunMigrateXDG(cobraCmd *cobra.Command, args []string) error {
+	moved := false
+
+	legacyDir, err := paths.LegacyDir()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(legacyDir); err == nil {
+		// oci.cacheDir() always stores pulled charts at <cache>/charts, so the
+		// legacy layout has them one level below ~/.devspace/cache; only that
+		// subdirectory's contents map 1:1 onto pathsCacheDirWithoutLegacy's
+		// target, not the whole cache directory
+		moves := map[string]func() (string, error){
+			filepath.Join("cache", "charts"): pathsCacheDirWithoutLegacy,
+			"plugins":                        pathsDataDirWithoutLegacy,
+			// generated.yaml files of projects that were namespaced under the
+			// legacy directory, see generated.configPath
+			"projects": func() (string, error) {
+				return xdgSubdir(os.Getenv("XDG_CONFIG_HOME"), ".config", "projects")
+			},
+		}
+
+		for legacySubdir, resolveTarget := range moves {
+			source := filepath.Join(legacyDir, legacySubdir)
+			if _, err := os.Stat(source); os.IsNotExist(err) {
+				continue
+			}
+
+			target, err := resolveTarget()
+			if err != nil {
+				return errors.Wrapf(err, "resolve xdg target for %s", legacySubdir)
+			}
+
+			err = moveDir(source, target)
+			if err != nil {
+				return errors.Wrapf(err, "move %s to %s", source, target)
+			}
+
+			log.GetInstance().Infof("Moved %s to %s", source, target)
+			moved = true
+		}
+
+		// Only the charts subdirectory was moved out of legacyDir/cache; remove
+		// the now-empty parent if that left nothing else behind
+		os.Remove(filepath.Join(legacyDir, "cache"))
+
+		// The paths package falls back to legacyDir for as long as it exists,
+		// so remove it once the moves above have left it empty
+		os.Remove(legacyDir)
+	}
+
+	// generated.yaml lives in the current project's own .devspace directory,
+	// not under ~/.devspace, so it migrates independently of the checks above
+	source, target, err := generated.MigrateLegacyConfig()
+	if err != nil {
+		return errors.Wrap(err, "migrate generated.yaml")
+	}
+	if source != "" {
+		log.GetInstance().Infof("Moved %s to %s", source, target)
+		moved = true
+	}
+
+	if !moved {
+		log.GetInstance().Info("No legacy devspace state found, nothing to migrate")
+	}
+
+	return nil
+}
+
+// pathsCacheDirWithoutLegacy and pathsDataDirWithoutLegacy resolve the XDG
+// target directories directly, bypassing the legacy fallback in the paths
+// package since we are migrating away from it
+func pathsCacheDirWithoutLegacy() (string, error) {
+	return xdgSubdir(os.Getenv("XDG_CACHE_HOME"), ".cache", "charts")
+}
+
+func pathsDataDirWithoutLegacy() (string, error) {
+	return xdgSubdir(os.Getenv("XDG_DATA_HOME"), filepath.Join(".local", "share"), "plugins")
+}
+
+func xdgSubdir(envValue, defaultRelHome, subdir string) (string, error) {
+	base := envValue
+	if base == "" {
+		home, err := paths.HomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, defaultRelHome)
+	}
+
+	dir := filepath.Join(base, "devspace", subdir)
+	return dir, os.MkdirAll(dir, 0755)
+}
+
+func moveDir(source, target string) error {
+	entries, err := ioutil.ReadDir(source)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(target, 0755)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		err = os.Rename(filepath.Join(source, entry.Name()), filepath.Join(target, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(source)
+}
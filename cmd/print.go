@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/schema"
+
+	"github.com/spf13/cobra"
+)
+
+// NewPrintCmd creates a new print command
+func NewPrintCmd() *cobra.Command {
+	printCmd := &cobra.Command{
+		Use:   "print",
+		Short: "Prints generated artifacts",
+	}
+
+	printCmd.AddCommand(&cobra.Command{
+		Use:   "schema",
+		Short: "Prints the JSON schema devspace.yaml is validated against",
+		Long: `
+#######################################################
+################# devspace print schema ###############
+#######################################################
+Prints the JSON Schema (draft-07) devspace.yaml is
+validated against, so editors such as VS Code or
+JetBrains IDEs can offer autocomplete and inline
+validation for it.
+#######################################################
+	`,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			fmt.Println(string(schema.JSON()))
+			return nil
+		},
+	})
+
+	return printCmd
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/devspace-cloud/devspace/cmd/flags"
+	"github.com/spf13/cobra"
+)
+
+// NewRootCmd creates the devspace root command and binds the flags shared by
+// every subcommand that talks to a kubernetes cluster
+// (--kubeconfig/--context/--namespace/--as/--as-group, --profile, --var)
+func NewRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "devspace",
+		Short: "Deploy and develop applications on Kubernetes",
+	}
+
+	globalFlags := flags.NewGlobalFlags(rootCmd)
+
+	rootCmd.AddCommand(NewDeployCmd(globalFlags))
+	rootCmd.AddCommand(NewRunCmd(globalFlags))
+	rootCmd.AddCommand(NewMigrateCmd())
+	rootCmd.AddCommand(NewPrintCmd())
+
+	return rootCmd
+}
@@ -1,22 +1,25 @@
 package configutil
 
 import (
-	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sync"
 
-	homedir "github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
 	yaml "gopkg.in/yaml.v2"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	"github.com/devspace-cloud/devspace/pkg/util/log"
 
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/constants"
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/schema"
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
 	"github.com/devspace-cloud/devspace/pkg/devspace/deploy/helm/merge"
+	"github.com/devspace-cloud/devspace/pkg/devspace/deploy/helm/oci"
+	"github.com/devspace-cloud/devspace/pkg/devspace/deploy/plugin"
+	"github.com/devspace-cloud/devspace/pkg/util/paths"
 	"github.com/devspace-cloud/devspace/pkg/util/yamlutil"
 )
 
@@ -80,6 +83,12 @@ type ConfigOptions struct {
 	Profile     string
 	KubeContext string
 
+	// KubeConfigFlags carries the merged --kubeconfig/--context/--namespace/
+	// --as/--as-group flags bound on the cobra root command. When set, it
+	// takes precedence over KubeContext for resolving cluster access, see
+	// RESTClientGetter.
+	KubeConfigFlags *genericclioptions.ConfigFlags `yaml:"-"`
+
 	LoadedVars map[string]string
 	Vars       []string
 }
@@ -96,10 +105,31 @@ func (co *ConfigOptions) Clone() (*ConfigOptions, error) {
 	if err != nil {
 		return nil, err
 	}
+	newCo.KubeConfigFlags = co.KubeConfigFlags
 
 	return newCo, nil
 }
 
+// RESTClientGetter returns a genericclioptions.RESTClientGetter that merges
+// $KUBECONFIG (colon-separated), --kubeconfig, --context, --namespace and
+// --as/--as-group exactly the way kubectl and helm themselves do. Both the
+// helm and kubectl deploy backends should build their clients from this
+// instead of handling namespace/context resolution ad-hoc.
+func RESTClientGetter(options *ConfigOptions) genericclioptions.RESTClientGetter {
+	if options != nil && options.KubeConfigFlags != nil {
+		return options.KubeConfigFlags
+	}
+
+	// Fall back to a ConfigFlags built from the legacy KubeContext string so
+	// callers that haven't been wired up to the cobra flags yet still work
+	configFlags := genericclioptions.NewConfigFlags(false)
+	if options != nil && options.KubeContext != "" {
+		configFlags.Context = &options.KubeContext
+	}
+
+	return configFlags
+}
+
 // GetBaseConfig returns the config
 func GetBaseConfig(options *ConfigOptions) (*latest.Config, error) {
 	return loadConfigOnce(options, false)
@@ -151,12 +181,20 @@ func GetConfigFromPath(generatedConfig *generated.Config, basePath string, optio
 		return nil, err
 	}
 
+	// Exhaustive required/enum/type validation against the generated JSON
+	// schema, before we even attempt to populate the typed structs
+	err = schema.Validate(rawMap)
+	if err != nil {
+		return nil, err
+	}
+
 	loadedConfig, err := ParseConfig(generatedConfig, rawMap, options, log)
 	if err != nil {
 		return nil, err
 	}
 
-	// Now we validate the config
+	// Cross-field semantic checks the schema cannot express (e.g. helm vs.
+	// kubectl vs. custom exclusivity, componentChart values round-trip)
 	err = validate(loadedConfig)
 	if err != nil {
 		return nil, err
@@ -175,6 +213,15 @@ func loadConfigOnce(options *ConfigOptions, allowProfile bool) (*latest.Config,
 			options = &ConfigOptions{}
 		}
 
+		// Load every deployment backend plugin before we validate, since
+		// validateCustomDeployment looks backends up by name via plugin.Get
+		// and a plugin registered too late would fail every custom deployment
+		err := plugin.LoadAll(log.GetInstance())
+		if err != nil {
+			getConfigOnceErr = err
+			return
+		}
+
 		// Get generated config
 		generatedConfig, err := generated.LoadConfig(options.Profile)
 		if err != nil {
@@ -210,6 +257,11 @@ func loadConfigOnce(options *ConfigOptions, allowProfile bool) (*latest.Config,
 	return config, getConfigOnceErr
 }
 
+// validate runs the cross-field semantic checks that a pure JSON schema
+// cannot express (schema.Validate already covers every required/enum/type
+// constraint on individual fields): helm/kubectl/custom exclusivity,
+// helmVersion-dependent constraints, plugin-backed custom deployments and
+// componentChart values round-tripping.
 func validate(config *latest.Config) error {
 	if config.Dev != nil {
 		if config.Dev.Ports != nil {
@@ -217,9 +269,6 @@ func validate(config *latest.Config) error {
 				if port.ImageName == "" && port.LabelSelector == nil {
 					return errors.Errorf("Error in config: imageName and label selector are nil in port config at index %d", index)
 				}
-				if port.PortMappings == nil {
-					return errors.Errorf("Error in config: portMappings is empty in port config at index %d", index)
-				}
 			}
 		}
 
@@ -230,62 +279,41 @@ func validate(config *latest.Config) error {
 				}
 			}
 		}
-
-		if config.Dev.Interactive != nil {
-			for index, imageConf := range config.Dev.Interactive.Images {
-				if imageConf.Name == "" {
-					return errors.Errorf("Error in config: Unnamed interactive image config at index %d", index)
-				}
-			}
-		}
-	}
-
-	if config.Commands != nil {
-		for index, command := range config.Commands {
-			if command.Name == "" {
-				return errors.Errorf("commands[%d].name is required", index)
-			}
-			if command.Command == "" {
-				return errors.Errorf("commands[%d].command is required", index)
-			}
-		}
-	}
-
-	if config.Hooks != nil {
-		for index, hookConfig := range config.Hooks {
-			if hookConfig.Command == "" {
-				return errors.Errorf("hooks[%d].command is required", index)
-			}
-		}
-	}
-
-	if config.Images != nil {
-		for imageConfigName, imageConf := range config.Images {
-			if imageConf.Image == "" {
-				return errors.Errorf("images.%s.image is required", imageConfigName)
-			}
-			if imageConf.Build != nil && imageConf.Build.Custom != nil && imageConf.Build.Custom.Command == "" {
-				return errors.Errorf("images.%s.build.custom.command is required", imageConfigName)
-			}
-			if imageConf.Image == "" {
-				return fmt.Errorf("images.%s.image is required", imageConfigName)
-			}
-		}
 	}
 
 	if config.Deployments != nil {
 		for index, deployConfig := range config.Deployments {
-			if deployConfig.Name == "" {
-				return errors.Errorf("deployments[%d].name is required", index)
+			if deployConfig.Helm == nil && deployConfig.Kubectl == nil && deployConfig.Custom == nil {
+				return errors.Errorf("Please specify either helm, kubectl or custom as deployment type in deployment %s", deployConfig.Name)
+			}
+			deploymentTypes := 0
+			for _, isSet := range []bool{deployConfig.Helm != nil, deployConfig.Kubectl != nil, deployConfig.Custom != nil} {
+				if isSet {
+					deploymentTypes++
+				}
 			}
-			if deployConfig.Helm == nil && deployConfig.Kubectl == nil {
-				return errors.Errorf("Please specify either helm or kubectl as deployment type in deployment %s", deployConfig.Name)
+			if deploymentTypes > 1 {
+				return errors.Errorf("Please specify only one of helm, kubectl or custom as deployment type in deployment %s", deployConfig.Name)
+			}
+			if deployConfig.Custom != nil {
+				if err := validateCustomDeployment(deployConfig); err != nil {
+					return err
+				}
 			}
 			if deployConfig.Helm != nil && (deployConfig.Helm.Chart == nil || deployConfig.Helm.Chart.Name == "") && (deployConfig.Helm.ComponentChart == nil || *deployConfig.Helm.ComponentChart == false) {
 				return errors.Errorf("deployments[%d].helm.chart and deployments[%d].helm.chart.name or deployments[%d].helm.componentChart is required", index, index, index)
 			}
-			if deployConfig.Kubectl != nil && deployConfig.Kubectl.Manifests == nil {
-				return errors.Errorf("deployments[%d].kubectl.manifests is required", index)
+			if deployConfig.Helm != nil && deployConfig.Helm.HelmVersion == latest.HelmV3 && deployConfig.Helm.TillerNamespace != "" {
+				return errors.Errorf("deployments[%d].helm.tillerNamespace cannot be used with helmVersion: v3, helm v3 stores releases in the deployment's own namespace instead of a central Tiller namespace", index)
+			}
+			if deployConfig.Helm != nil && deployConfig.Helm.Chart != nil && deployConfig.Helm.Chart.IsOCI() && deployConfig.Helm.Chart.Version != "" {
+				ref, err := oci.ParseReference(deployConfig.Helm.Chart.Name)
+				if err != nil {
+					return errors.Errorf("deployments[%d].helm.chart.name: %v", index, err)
+				}
+				if ref.HasTag() {
+					return errors.Errorf("deployments[%d].helm.chart.version cannot be set, the oci reference in deployments[%d].helm.chart.name already specifies a tag", index, index)
+				}
 			}
 			if deployConfig.Helm != nil && deployConfig.Helm.ComponentChart != nil && *deployConfig.Helm.ComponentChart == true {
 				// Load override values from path
@@ -327,6 +355,31 @@ func validate(config *latest.Config) error {
 	return nil
 }
 
+// validateCustomDeployment dispatches DeploymentConfig.Custom to the plugin
+// backend(s) it names, delegating the actual config shape checks to
+// Backend.ValidateConfig since devspace itself has no way to know what a
+// third-party backend expects
+func validateCustomDeployment(deployConfig *latest.DeploymentConfig) error {
+	for backendName, customConfig := range deployConfig.Custom {
+		backend, ok := plugin.Get(backendName)
+		if !ok {
+			return errors.Errorf("deployments.%s.custom.%s: no plugin registered for backend '%s'", deployConfig.Name, backendName, backendName)
+		}
+
+		configMap, ok := customConfig.(map[interface{}]interface{})
+		if !ok {
+			return errors.Errorf("deployments.%s.custom.%s must be a map", deployConfig.Name, backendName)
+		}
+
+		err := backend.ValidateConfig(configMap)
+		if err != nil {
+			return errors.Errorf("deployments.%s.custom.%s: %v", deployConfig.Name, backendName, err)
+		}
+	}
+
+	return nil
+}
+
 // SetDevSpaceRoot checks the current directory and all parent directories for a .devspace folder with a config and sets the current working directory accordingly
 func SetDevSpaceRoot(log log.Logger) (bool, error) {
 	cwd, err := os.Getwd()
@@ -335,7 +388,7 @@ func SetDevSpaceRoot(log log.Logger) (bool, error) {
 	}
 
 	originalCwd := cwd
-	homedir, err := homedir.Dir()
+	homedir, err := paths.HomeDir()
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,169 @@
+package generated
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/devspace-cloud/devspace/pkg/util/paths"
+)
+
+// ConfigPath is the legacy, pre-XDG relative path to the generated config
+// file. A project that already has one is kept there for backwards
+// compatibility; every other project stores generated.yaml under
+// paths.ConfigDir() instead, see configPath.
+const ConfigPath = ".devspace/generated.yaml"
+
+// Config holds the information that devspace generates and caches between runs,
+// such as active profiles and per-deployment state
+type Config struct {
+	ActiveProfile string                  `yaml:"activeProfile,omitempty"`
+	Profiles      map[string]*CacheConfig `yaml:"profiles,omitempty"`
+}
+
+// CacheConfig holds the cached state for a single profile
+type CacheConfig struct {
+	Deployments map[string]*DeploymentCache `yaml:"deployments,omitempty"`
+}
+
+// DeploymentCache holds the cached state for a single deployment
+type DeploymentCache struct {
+	DeploymentConfigHash string `yaml:"deploymentConfigHash,omitempty"`
+}
+
+// LoadConfig loads the generated config for the given profile, returning an
+// empty config if none exists yet
+func LoadConfig(profile string) (*Config, error) {
+	config := &Config{
+		ActiveProfile: profile,
+		Profiles:      map[string]*CacheConfig{},
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
+		return nil, err
+	}
+
+	err = yaml.Unmarshal(data, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// SaveConfig persists the generated config to disk
+func SaveConfig(config *Config) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0666)
+}
+
+// configPath resolves where this project's generated.yaml lives. A project
+// that already has the legacy ./.devspace/generated.yaml keeps using it;
+// every other project is namespaced under paths.ConfigDir()/projects/<hash
+// of its absolute path>, since that directory is shared by every devspace
+// project on the machine
+func configPath() (string, error) {
+	if _, err := os.Stat(ConfigPath); err == nil {
+		return ConfigPath, nil
+	}
+
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	projectDir, err := projectDir(configDir)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(projectDir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(projectDir, "generated.yaml"), nil
+}
+
+// projectDir returns configDir/projects/<hash>, where hash identifies the
+// current project by the absolute path to its working directory
+func projectDir(configDir string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	absCwd, err := filepath.Abs(cwd)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(absCwd))
+	return filepath.Join(configDir, "projects", hex.EncodeToString(sum[:])[:16]), nil
+}
+
+// MigrateLegacyConfig moves the current project's legacy
+// ./.devspace/generated.yaml into its XDG-namespaced location under
+// paths.ConfigDir(), for `devspace migrate xdg`. It is a no-op if no legacy
+// generated.yaml exists for this project.
+func MigrateLegacyConfig() (string, string, error) {
+	if _, err := os.Stat(ConfigPath); os.IsNotExist(err) {
+		return "", "", nil
+	}
+
+	configDir, err := paths.ConfigDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	projectDir, err := projectDir(configDir)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = os.MkdirAll(projectDir, 0755)
+	if err != nil {
+		return "", "", err
+	}
+
+	target := filepath.Join(projectDir, "generated.yaml")
+
+	data, err := ioutil.ReadFile(ConfigPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = ioutil.WriteFile(target, data, 0666)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = os.Remove(ConfigPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	return ConfigPath, target, nil
+}
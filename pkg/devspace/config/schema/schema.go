@@ -0,0 +1,93 @@
+// Package schema validates a parsed devspace.yaml against schema.json, a
+// hand-maintained JSON Schema (draft-07) giving exhaustive required/enum/
+// type coverage for every field with precise error paths, instead of the
+// hand-written, partial checks that used to live in configutil.validate.
+//
+// schema.json is maintained by hand, not generated: invopop/jsonschema keys
+// properties off a struct's `json` tag (falling back to the bare,
+// capitalized Go field name), so reflecting
+// pkg/devspace/config/versions/latest's yaml-tagged structs produces
+// PascalCase properties, `additionalProperties: false`, and `$defs` instead
+// of the lowercase, permissive, `definitions`-based schema devspace.yaml is
+// actually validated against. Whenever a field is added, renamed, or its
+// required/enum constraints change in latest.Config, update schema.json by
+// hand to match.
+package schema
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+//go:embed schema.json
+var schemaJSON []byte
+
+// JSON returns the embedded schema, for `devspace print schema`
+func JSON() []byte {
+	return schemaJSON
+}
+
+// Validate validates rawMap (as parsed by yaml.v2 from devspace.yaml) against
+// the embedded JSON Schema
+func Validate(rawMap map[interface{}]interface{}) error {
+	normalized, err := normalize(rawMap)
+	if err != nil {
+		return err
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schemaJSON), gojsonschema.NewGoLoader(normalized))
+	if err != nil {
+		return errors.Wrap(err, "validate devspace.yaml against schema")
+	}
+
+	if !result.Valid() {
+		messages := make([]string, 0, len(result.Errors()))
+		for _, resultError := range result.Errors() {
+			messages = append(messages, fmt.Sprintf("%s %s", resultError.Field(), resultError.Description()))
+		}
+
+		return errors.Errorf("devspace.yaml is invalid:\n  %s", strings.Join(messages, "\n  "))
+	}
+
+	return nil
+}
+
+// normalize converts the map[interface{}]interface{} / []interface{} tree
+// yaml.v2 produces into map[string]interface{}, which is what
+// encoding/json (and therefore gojsonschema) expects
+func normalize(in interface{}) (interface{}, error) {
+	switch value := in.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for key, val := range value {
+			strKey, ok := key.(string)
+			if !ok {
+				return nil, errors.Errorf("non-string map key %v in devspace.yaml", key)
+			}
+
+			normalizedVal, err := normalize(val)
+			if err != nil {
+				return nil, err
+			}
+
+			out[strKey] = normalizedVal
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, val := range value {
+			normalizedVal, err := normalize(val)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = normalizedVal
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
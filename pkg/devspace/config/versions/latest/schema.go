@@ -0,0 +1,157 @@
+package latest
+
+import "strings"
+
+// Version is the current api version
+const Version string = "v1beta9"
+
+// New returns a new config object
+func New() interface{} {
+	return &Config{
+		Version: Version,
+	}
+}
+
+// Config defines the configuration
+type Config struct {
+	Version     string                  `yaml:"version"`
+	Images      map[string]*ImageConfig `yaml:"images,omitempty"`
+	Deployments []*DeploymentConfig     `yaml:"deployments,omitempty"`
+	Dev         *DevConfig              `yaml:"dev,omitempty"`
+	Hooks       []*HookConfig           `yaml:"hooks,omitempty"`
+	Commands    []*CommandConfig        `yaml:"commands,omitempty"`
+}
+
+// ImageConfig defines the image specification
+type ImageConfig struct {
+	Image string       `yaml:"image"`
+	Build *BuildConfig `yaml:"build,omitempty"`
+}
+
+// BuildConfig defines the build process for an image
+type BuildConfig struct {
+	Custom *CustomConfig `yaml:"custom,omitempty"`
+}
+
+// CustomConfig tells devspace to build the image with a custom command
+type CustomConfig struct {
+	Command string `yaml:"command"`
+}
+
+// DeploymentConfig defines the configuration how the devspace should be deployed
+type DeploymentConfig struct {
+	Name    string         `yaml:"name"`
+	Helm    *HelmConfig    `yaml:"helm,omitempty"`
+	Kubectl *KubectlConfig `yaml:"kubectl,omitempty"`
+	// Custom holds the config block for a deployment backend loaded from a
+	// *.so plugin in the devspace plugin directory, keyed by the backend's name
+	Custom map[string]interface{} `yaml:"custom,omitempty"`
+}
+
+// HelmV2 selects the classic, Tiller-backed helm client
+const HelmV2 string = "v2"
+
+// HelmV3 selects the Tiller-less helm client that stores releases as secrets in the
+// target namespace
+const HelmV3 string = "v3"
+
+// HelmConfig defines the specific helm options used during deployment
+type HelmConfig struct {
+	HelmVersion     string                      `yaml:"helmVersion,omitempty"`
+	Chart           *ChartConfig                `yaml:"chart,omitempty"`
+	ComponentChart  *bool                       `yaml:"componentChart,omitempty"`
+	Values          map[interface{}]interface{} `yaml:"values,omitempty"`
+	ValuesFiles     []string                    `yaml:"valuesFiles,omitempty"`
+	TillerNamespace string                      `yaml:"tillerNamespace,omitempty"`
+}
+
+// ComponentConfig is the schema the values of a componentChart deployment are
+// validated against
+type ComponentConfig struct {
+	Containers []interface{} `yaml:"containers,omitempty"`
+	Service    interface{}   `yaml:"service,omitempty"`
+}
+
+// ChartTypeOCI marks a ChartConfig as being pulled from an OCI registry
+// instead of a classic helm chart repository
+const ChartTypeOCI string = "oci"
+
+// ociPrefix is the scheme chart references use to point at an OCI registry,
+// e.g. oci://registry.example.com/charts/component-chart:v0.0.6
+const ociPrefix string = "oci://"
+
+// ChartConfig defines the helm chart to use
+type ChartConfig struct {
+	Name      string          `yaml:"name"`
+	Version   string          `yaml:"version,omitempty"`
+	RepoURL   string          `yaml:"repo,omitempty"`
+	ChartType string          `yaml:"chartType,omitempty"`
+	Registry  *RegistryConfig `yaml:"registry,omitempty"`
+}
+
+// RegistryConfig holds the credentials used to log in to an OCI registry
+// before pulling a chart. If omitted, the existing docker credentials in
+// ~/.docker/config.json are used instead.
+type RegistryConfig struct {
+	URL      string `yaml:"url,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+}
+
+// IsOCI returns true if Name points at an OCI registry, either because it was
+// given the oci:// scheme or ChartType was set explicitly
+func (c *ChartConfig) IsOCI() bool {
+	return c.ChartType == ChartTypeOCI || strings.HasPrefix(c.Name, ociPrefix)
+}
+
+// KubectlConfig defines the specific kubectl options used during deployment
+type KubectlConfig struct {
+	Manifests []string `yaml:"manifests,omitempty"`
+}
+
+// DevConfig defines the devspace deployment for the development environment
+type DevConfig struct {
+	Ports       []*PortForwardingConfig `yaml:"ports,omitempty"`
+	Sync        []*SyncConfig           `yaml:"sync,omitempty"`
+	Interactive *InteractiveConfig      `yaml:"interactive,omitempty"`
+}
+
+// PortForwardingConfig defines the ports for a port forwarding to a DevSpace
+type PortForwardingConfig struct {
+	ImageName     string            `yaml:"imageName,omitempty"`
+	LabelSelector map[string]string `yaml:"labelSelector,omitempty"`
+	PortMappings  []*PortMapping    `yaml:"portMappings,omitempty"`
+}
+
+// PortMapping defines the ports for a PortMapping
+type PortMapping struct {
+	LocalPort  *int `yaml:"port,omitempty"`
+	RemotePort *int `yaml:"remotePort,omitempty"`
+}
+
+// SyncConfig defines the paths for a SyncFolder
+type SyncConfig struct {
+	ImageName     string            `yaml:"imageName,omitempty"`
+	LabelSelector map[string]string `yaml:"labelSelector,omitempty"`
+}
+
+// InteractiveConfig defines the devspace interactive mode configuration
+type InteractiveConfig struct {
+	Images []*InteractiveImageConfig `yaml:"images,omitempty"`
+}
+
+// InteractiveImageConfig defines the interactive mode configuration for an image
+type InteractiveImageConfig struct {
+	Name string `yaml:"name"`
+}
+
+// HookConfig defines a hook that is executed during the devspace deployment
+type HookConfig struct {
+	Command string `yaml:"command"`
+}
+
+// CommandConfig defines the command that can be run with devspace run
+type CommandConfig struct {
+	Name    string `yaml:"name"`
+	Command string `yaml:"command"`
+}
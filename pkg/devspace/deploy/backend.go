@@ -0,0 +1,32 @@
+package deploy
+
+import (
+	"context"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+)
+
+// Backend is implemented by deployment backends that are loaded as Go
+// plugins, letting third parties add deployment methods (kustomize, jsonnet,
+// terraform, argocd apply, ...) alongside the built-in helm and kubectl
+// backends
+type Backend interface {
+	// Name returns the key used to select this backend in
+	// DeploymentConfig.Custom
+	Name() string
+
+	// Deploy deploys deployConfig via this backend
+	Deploy(ctx context.Context, deployConfig *latest.DeploymentConfig, kubeClient *kubectl.Client, log log.Logger) error
+
+	// Delete removes a previously deployed release
+	Delete(ctx context.Context, deployConfig *latest.DeploymentConfig, kubeClient *kubectl.Client, log log.Logger) error
+
+	// Status returns a human readable status for the deployment
+	Status(ctx context.Context, deployConfig *latest.DeploymentConfig, kubeClient *kubectl.Client, log log.Logger) (string, error)
+
+	// ValidateConfig validates the backend-specific block found under
+	// DeploymentConfig.Custom[Name()]
+	ValidateConfig(config map[interface{}]interface{}) error
+}
@@ -1,12 +1,17 @@
 package helm
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/generated"
 	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/deploy/helm/oci"
 	"github.com/devspace-cloud/devspace/pkg/devspace/helm"
 	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
 	"github.com/devspace-cloud/devspace/pkg/util/log"
 	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
 )
 
 // DevSpaceChartConfig is the config that holds the devspace chart information
@@ -41,6 +46,16 @@ func New(config *latest.Config, kubeClient *kubectl.Client, deployConfig *latest
 		deployConfig.Helm.Chart = DevSpaceChartConfig
 	}
 
+	// Pull OCI charts up front so the helm client only ever sees a local .tgz
+	if deployConfig.Helm.Chart != nil && deployConfig.Helm.Chart.IsOCI() {
+		localPath, err := oci.PullChart(deployConfig.Helm.Chart)
+		if err != nil {
+			return nil, errors.Wrap(err, "pull oci chart")
+		}
+
+		deployConfig.Helm.Chart.Name = localPath
+	}
+
 	return &DeployConfig{
 		Kube:             kubeClient,
 		TillerNamespace:  tillerNamespace,
@@ -50,30 +65,105 @@ func New(config *latest.Config, kubeClient *kubectl.Client, deployConfig *latest
 	}, nil
 }
 
-// Delete deletes the release
-func (d *DeployConfig) Delete(cache *generated.CacheConfig) error {
-	// Delete with helm engine
-	isDeployed := helm.IsTillerDeployed(d.config, d.Kube, d.TillerNamespace)
-	if isDeployed == false {
+// isV3 returns true if the deployment is configured to use the Tiller-less
+// helm v3 client instead of the classic Tiller-backed one
+func (d *DeployConfig) isV3() bool {
+	return d.DeploymentConfig.Helm.HelmVersion == latest.HelmV3
+}
+
+// helmClient returns the already initialized helm client, creating it for
+// the configured HelmVersion if necessary
+func (d *DeployConfig) helmClient() (helm.Interface, error) {
+	if d.Helm != nil {
+		return d.Helm, nil
+	}
+
+	var err error
+	if d.isV3() {
+		d.Helm, err = helm.NewV3Client(d.Kube, d.Kube.Namespace, d.Log)
+	} else {
+		d.Helm, err = helm.NewClient(d.config, d.Kube, d.TillerNamespace, d.Log, false)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "new helm client")
+	}
+
+	return d.Helm, nil
+}
+
+// Deploy installs or upgrades the release, skipping the install entirely if
+// cache already has a record of this exact chart having been deployed
+func (d *DeployConfig) Deploy(cache *generated.CacheConfig) error {
+	hash := chartHash(d.DeploymentConfig.Helm)
+
+	if cache.Deployments != nil && cache.Deployments[d.DeploymentConfig.Name] != nil &&
+		cache.Deployments[d.DeploymentConfig.Name].DeploymentConfigHash == hash {
 		return nil
 	}
 
-	if d.Helm == nil {
-		var err error
+	helmClient, err := d.helmClient()
+	if err != nil {
+		return err
+	}
+	d.Helm = helmClient
 
-		// Get HelmClient
-		d.Helm, err = helm.NewClient(d.config, d.Kube, d.TillerNamespace, d.Log, false)
-		if err != nil {
-			return errors.Wrap(err, "new helm client")
+	_, err = d.Helm.InstallChart(d.DeploymentConfig.Name, d.Kube.Namespace, d.DeploymentConfig.Helm.Values, d.DeploymentConfig.Helm)
+	if err != nil {
+		return err
+	}
+
+	if cache.Deployments == nil {
+		cache.Deployments = map[string]*generated.DeploymentCache{}
+	}
+	cache.Deployments[d.DeploymentConfig.Name] = &generated.DeploymentCache{
+		DeploymentConfigHash: hash,
+	}
+
+	return nil
+}
+
+// Delete deletes the release
+func (d *DeployConfig) Delete(cache *generated.CacheConfig) error {
+	// Helm v3 has no central Tiller deployment to check for, releases simply
+	// live as secrets in the target namespace
+	if !d.isV3() {
+		isDeployed := helm.IsTillerDeployed(d.config, d.Kube, d.TillerNamespace)
+		if isDeployed == false {
+			return nil
 		}
 	}
 
-	_, err := d.Helm.DeleteRelease(d.DeploymentConfig.Name, true)
+	helmClient, err := d.helmClient()
+	if err != nil {
+		return err
+	}
+	d.Helm = helmClient
+
+	_, err = d.Helm.DeleteRelease(d.DeploymentConfig.Name, true)
 	if err != nil {
 		return err
 	}
 
 	// Delete from cache
-	delete(cache.Deployments, d.DeploymentConfig.Helm.Chart.Name)
+	delete(cache.Deployments, d.DeploymentConfig.Name)
 	return nil
 }
+
+// chartHash returns a short fingerprint of helmConfig, used to tell whether
+// anything that affects the rendered release (the chart itself, its values,
+// or which helm client installs it) has changed since the last deploy
+func chartHash(helmConfig *latest.HelmConfig) string {
+	if helmConfig == nil {
+		return ""
+	}
+
+	// Marshal instead of hand-concatenating fields so that adding a new field
+	// to HelmConfig is automatically covered instead of silently ignored
+	raw, err := yaml.Marshal(helmConfig)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])[:16]
+}
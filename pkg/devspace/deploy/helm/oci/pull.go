@@ -0,0 +1,400 @@
+package oci
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/util/paths"
+	"github.com/pkg/errors"
+)
+
+// helmChartContentMediaType is the OCI layer media type helm uses to store a
+// packaged chart, per the OCI distribution spec helm v3 adopted for `helm
+// chart push`/`helm chart pull`
+const helmChartContentMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+// cacheDir returns the directory pulled OCI charts are cached in, under the
+// devspace cache directory
+func cacheDir() (string, error) {
+	base, err := paths.CacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(base, "charts")
+	return dir, os.MkdirAll(dir, 0755)
+}
+
+// Reference is a parsed oci:// chart reference
+type Reference struct {
+	Registry   string
+	Repository string
+	Tag        string
+}
+
+// HasTag returns true if the reference already specifies a tag, e.g. the
+// ":v0.0.6" in oci://registry.example.com/charts/component-chart:v0.0.6
+func (r *Reference) HasTag() bool {
+	return r.Tag != ""
+}
+
+// ParseReference parses an oci://registry/repo:tag chart name into its parts.
+// The registry is split off before any ":" is looked for, so a registry port
+// (oci://registry.example.com:5000/chart) is never mistaken for a tag.
+func ParseReference(chartName string) (*Reference, error) {
+	trimmed := strings.TrimPrefix(chartName, "oci://")
+
+	registry, rest := splitFirst(trimmed, "/")
+	if rest == "" {
+		return nil, errors.Errorf("invalid oci chart reference %s: missing repository", chartName)
+	}
+
+	repository, tag := rest, ""
+	if idx := strings.LastIndex(rest, ":"); idx != -1 {
+		repository, tag = rest[:idx], rest[idx+1:]
+	}
+
+	return &Reference{Registry: registry, Repository: repository, Tag: tag}, nil
+}
+
+func splitFirst(s, sep string) (string, string) {
+	idx := strings.Index(s, sep)
+	if idx == -1 {
+		return s, ""
+	}
+	return s[:idx], s[idx+1:]
+}
+
+// PullChart resolves an OCI chart reference (chart.Name, either oci://... or
+// chartType: oci) to a local .tgz file, pulling and caching it if necessary
+func PullChart(chart *latest.ChartConfig) (string, error) {
+	ref, err := ParseReference(chart.Name)
+	if err != nil {
+		return "", err
+	}
+	if chart.Version != "" {
+		if ref.Tag != "" {
+			return "", errors.Errorf("deployments helm.chart.version cannot be set when the oci reference %s already specifies a tag", chart.Name)
+		}
+		ref.Tag = chart.Version
+	}
+	if ref.Tag == "" {
+		ref.Tag = "latest"
+	}
+
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	localPath := filepath.Join(dir, fmt.Sprintf("%s_%s-%s.tgz", sanitizeForFilename(ref.Registry), sanitizeForFilename(ref.Repository), ref.Tag))
+	if _, err := os.Stat(localPath); err == nil {
+		return localPath, nil
+	}
+
+	username, password, err := credentials(ref.Registry, chart.Registry)
+	if err != nil {
+		return "", errors.Wrap(err, "resolve registry credentials")
+	}
+
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.Registry, ref.Repository, ref.Tag)
+	auth, err := login(manifestURL, username, password)
+	if err != nil {
+		return "", errors.Wrap(err, "registry login")
+	}
+
+	manifest, err := fetchManifest(ref, auth)
+	if err != nil {
+		return "", errors.Wrap(err, "fetch manifest")
+	}
+
+	layer, err := manifest.chartLayer()
+	if err != nil {
+		return "", err
+	}
+
+	err = downloadBlob(ref, auth, layer.Digest, localPath)
+	if err != nil {
+		return "", errors.Wrap(err, "download chart blob")
+	}
+
+	return localPath, nil
+}
+
+// sanitizeForFilename makes a registry host (which may contain a ":<port>")
+// or a repository path (which contains "/" separators) safe to use as part
+// of a cache filename. The full repository path is kept, not just its last
+// segment, so two repositories that only share a final path component (e.g.
+// team-a/chart and team-b/chart on the same registry) don't collide on the
+// same cache file.
+func sanitizeForFilename(s string) string {
+	return strings.NewReplacer(":", "_", "/", "_").Replace(s)
+}
+
+type ociManifest struct {
+	Layers []ociLayer `json:"layers"`
+}
+
+type ociLayer struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+func (m *ociManifest) chartLayer() (*ociLayer, error) {
+	for _, layer := range m.Layers {
+		if layer.MediaType == helmChartContentMediaType {
+			return &layer, nil
+		}
+	}
+	return nil, errors.Errorf("no helm chart content layer found in oci manifest")
+}
+
+func fetchManifest(ref *Reference, auth *registryAuth) (*ociManifest, error) {
+	url := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.Registry, ref.Repository, ref.Tag)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.oci.image.manifest.v1+json")
+	auth.setHeader(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("registry returned status %d for %s", resp.StatusCode, url)
+	}
+
+	manifest := &ociManifest{}
+	if err := json.NewDecoder(resp.Body).Decode(manifest); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func downloadBlob(ref *Reference, auth *registryAuth, digest, destPath string) error {
+	url := fmt.Sprintf("https://%s/v2/%s/blobs/%s", ref.Registry, ref.Repository, digest)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	auth.setHeader(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("registry returned status %d for %s", resp.StatusCode, url)
+	}
+
+	out, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, resp.Body)
+	return err
+}
+
+// registryAuth is the credential devspace presents on every subsequent
+// registry request: either a bearer token obtained via the WWW-Authenticate
+// challenge below, or (for registries that skip the challenge and expect
+// HTTP Basic directly) the raw username/password
+type registryAuth struct {
+	bearerToken string
+	username    string
+	password    string
+}
+
+func (a *registryAuth) setHeader(req *http.Request) {
+	if a == nil {
+		return
+	}
+	if a.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+a.bearerToken)
+	} else if a.username != "" || a.password != "" {
+		req.SetBasicAuth(a.username, a.password)
+	}
+}
+
+// login probes requestURL unauthenticated and, if the registry challenges
+// with a WWW-Authenticate: Bearer header (the standard OCI distribution-spec
+// flow), exchanges username/password for a short-lived bearer token at the
+// challenge's realm. Registries that respond with WWW-Authenticate: Basic,
+// or that don't challenge at all, fall back to sending username/password as
+// HTTP Basic on every request instead.
+func login(requestURL, username, password string) (*registryAuth, error) {
+	if username == "" && password == "" {
+		return &registryAuth{}, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return &registryAuth{username: username, password: password}, nil
+	}
+
+	challenge := parseBearerChallenge(resp.Header.Get("Www-Authenticate"))
+	if challenge == nil {
+		return &registryAuth{username: username, password: password}, nil
+	}
+
+	token, err := exchangeToken(challenge, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &registryAuth{bearerToken: token}, nil
+}
+
+// bearerChallenge is the parsed realm/service/scope triple out of a
+// `WWW-Authenticate: Bearer realm="...",service="...",scope="..."` header
+type bearerChallenge struct {
+	realm   string
+	service string
+	scope   string
+}
+
+func parseBearerChallenge(header string) *bearerChallenge {
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil
+	}
+
+	challenge := &bearerChallenge{}
+	for _, part := range strings.Split(strings.TrimPrefix(header, "Bearer "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		value := strings.Trim(kv[1], `"`)
+		switch kv[0] {
+		case "realm":
+			challenge.realm = value
+		case "service":
+			challenge.service = value
+		case "scope":
+			challenge.scope = value
+		}
+	}
+
+	if challenge.realm == "" {
+		return nil
+	}
+	return challenge
+}
+
+// exchangeToken trades username/password for a bearer token at the
+// challenge's realm, the same token endpoint request `docker login`/`helm
+// registry login` make
+func exchangeToken(challenge *bearerChallenge, username, password string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, challenge.realm, nil)
+	if err != nil {
+		return "", err
+	}
+
+	query := req.URL.Query()
+	if challenge.service != "" {
+		query.Set("service", challenge.service)
+	}
+	if challenge.scope != "" {
+		query.Set("scope", challenge.scope)
+	}
+	req.URL.RawQuery = query.Encode()
+	req.SetBasicAuth(username, password)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("token exchange with %s returned status %d", challenge.realm, resp.StatusCode)
+	}
+
+	tokenResp := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", err
+	}
+
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	return tokenResp.AccessToken, nil
+}
+
+func credentials(registry string, inline *latest.RegistryConfig) (string, string, error) {
+	if inline != nil && (inline.Username != "" || inline.Password != "") {
+		return inline.Username, inline.Password, nil
+	}
+
+	home, err := paths.HomeDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	configPath := filepath.Join(home, ".docker", "config.json")
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", nil
+		}
+		return "", "", err
+	}
+
+	dockerConfig := struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}{}
+	if err := json.Unmarshal(data, &dockerConfig); err != nil {
+		return "", "", err
+	}
+
+	entry, ok := dockerConfig.Auths[registry]
+	if !ok {
+		return "", "", nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+	if err != nil {
+		return "", "", err
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return "", "", errors.Errorf("invalid docker config auth entry for %s", registry)
+	}
+
+	return parts[0], parts[1], nil
+}
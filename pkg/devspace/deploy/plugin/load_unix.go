@@ -0,0 +1,72 @@
+// +build linux darwin
+
+package plugin
+
+import (
+	"path/filepath"
+	"plugin"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/deploy"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/devspace-cloud/devspace/pkg/util/paths"
+	"github.com/pkg/errors"
+)
+
+// pluginSymbol is the exported symbol every plugin .so must provide. It is
+// looked up as `func() deploy.Backend` and invoked once at load time.
+const pluginSymbol = "DevSpacePlugin"
+
+// pluginDir returns the directory plugin .so files are loaded from
+func pluginDir() (string, error) {
+	base, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "plugins"), nil
+}
+
+// LoadAll scans the devspace plugin directory for *.so files built with
+// `go build -buildmode=plugin` and registers the backend each one exports
+func LoadAll(log log.Logger) error {
+	dir, err := pluginDir()
+	if err != nil {
+		return err
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range matches {
+		backend, err := load(path)
+		if err != nil {
+			return errors.Wrapf(err, "load plugin %s", path)
+		}
+
+		Register(backend)
+		log.Infof("Loaded deployment plugin '%s' from %s", backend.Name(), path)
+	}
+
+	return nil
+}
+
+func load(path string) (deploy.Backend, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := p.Lookup(pluginSymbol)
+	if err != nil {
+		return nil, errors.Wrapf(err, "plugin does not export %s", pluginSymbol)
+	}
+
+	newBackend, ok := sym.(func() deploy.Backend)
+	if !ok {
+		return nil, errors.Errorf("%s must have type func() deploy.Backend", pluginSymbol)
+	}
+
+	return newBackend(), nil
+}
@@ -0,0 +1,12 @@
+// +build windows
+
+package plugin
+
+import "github.com/devspace-cloud/devspace/pkg/util/log"
+
+// LoadAll is a no-op on Windows: the Go plugin package only supports linux
+// and darwin, so devspace.yaml's Custom deployments simply stay unavailable
+// there
+func LoadAll(log log.Logger) error {
+	return nil
+}
@@ -0,0 +1,44 @@
+package plugin
+
+import (
+	"sync"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/deploy"
+)
+
+// registry holds every deployment backend loaded from the devspace plugin
+// directory (paths.DataDir()/plugins), keyed by Backend.Name()
+var (
+	registry      = map[string]deploy.Backend{}
+	registryMutex sync.Mutex
+)
+
+// Register adds a backend to the registry, overwriting any backend already
+// registered under the same name
+func Register(backend deploy.Backend) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	registry[backend.Name()] = backend
+}
+
+// Get returns the backend registered under name, if any
+func Get(name string) (deploy.Backend, bool) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	backend, ok := registry[name]
+	return backend, ok
+}
+
+// Names returns the names of all currently registered backends
+func Names() []string {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,61 @@
+package helm
+
+import (
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Interface is the common abstraction over the helm v2 (Tiller-backed) and
+// helm v3 (Tiller-less) clients
+type Interface interface {
+	InstallChart(releaseName string, releaseNamespace string, values map[interface{}]interface{}, helmConfig *latest.HelmConfig) (*Release, error)
+	DeleteRelease(releaseName string, purge bool) (*Release, error)
+}
+
+// Release is a minimal representation of a deployed helm release
+type Release struct {
+	Name      string
+	Namespace string
+	Version   int
+}
+
+// NewClient creates a new tiller-backed (helm v2) client. It does not install
+// or upgrade Tiller; config and upgradeTiller are not used.
+func NewClient(config *latest.Config, kubeClient *kubectl.Client, tillerNamespace string, log log.Logger, upgradeTiller bool) (Interface, error) {
+	return &v2Client{
+		kubeClient:      kubeClient,
+		tillerNamespace: tillerNamespace,
+		log:             log,
+	}, nil
+}
+
+// IsTillerDeployed checks whether Tiller is running in the given namespace
+func IsTillerDeployed(config *latest.Config, kubeClient *kubectl.Client, tillerNamespace string) bool {
+	_, err := kubeClient.Client.AppsV1().Deployments(tillerNamespace).Get("tiller-deploy", metav1.GetOptions{})
+	return err == nil
+}
+
+type v2Client struct {
+	kubeClient      *kubectl.Client
+	tillerNamespace string
+	log             log.Logger
+}
+
+// errV2Unsupported is returned by every v2Client operation: this repo doesn't
+// vendor a client for Tiller's gRPC API, so the classic, Tiller-backed helm
+// path (HelmVersion == "" or "v2") can't actually install or delete releases
+// yet. Reporting that honestly beats a stub that claims success and leaves
+// the cluster untouched.
+var errV2Unsupported = errors.New("helm v2 is not supported yet, set helmVersion: v3")
+
+func (c *v2Client) InstallChart(releaseName string, releaseNamespace string, values map[interface{}]interface{}, helmConfig *latest.HelmConfig) (*Release, error) {
+	return nil, errV2Unsupported
+}
+
+func (c *v2Client) DeleteRelease(releaseName string, purge bool) (*Release, error) {
+	return nil, errV2Unsupported
+}
@@ -0,0 +1,330 @@
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/devspace-cloud/devspace/pkg/devspace/config/versions/latest"
+	"github.com/devspace-cloud/devspace/pkg/devspace/kubectl"
+	"github.com/devspace-cloud/devspace/pkg/util/log"
+	"github.com/pkg/errors"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// releaseSecretType is the secret type helm v3 uses to store release state,
+// mirroring helm's own storage/driver/secrets.go
+const releaseSecretType corev1.SecretType = "helm.sh/release.v1"
+
+// releaseSecretLabel marks a secret as belonging to a helm release so it can
+// be listed without scanning every secret in the namespace
+const releaseSecretLabel = "owner=helm,status=deployed"
+
+// v3Client talks to the cluster directly and stores release history as
+// Kubernetes secrets in the release's own namespace, the way `helm` itself
+// does since v3 removed Tiller
+type v3Client struct {
+	kubeClient *kubectl.Client
+	namespace  string
+	log        log.Logger
+}
+
+// release is the payload helm stores (gzip+base64 encoded) inside each
+// `sh.helm.release.v1.<name>.v<revision>` secret
+type release struct {
+	Name      string                 `json:"name"`
+	Version   int                    `json:"version"`
+	Namespace string                 `json:"namespace"`
+	Config    map[string]interface{} `json:"config"`
+	Chart     *chartMeta             `json:"chart"`
+	// Manifest is the concatenation of every rendered template, the same way
+	// helm itself records what it applied so a later uninstall knows what to
+	// tear down
+	Manifest string `json:"manifest"`
+}
+
+type chartMeta struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// NewV3Client creates a Tiller-less helm client that reads and writes release
+// history as secrets in namespace, the same layout `helm` v3 uses
+func NewV3Client(kubeClient *kubectl.Client, namespace string, log log.Logger) (Interface, error) {
+	if namespace == "" {
+		namespace = kubeClient.Namespace
+	}
+
+	return &v3Client{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		log:        log,
+	}, nil
+}
+
+func (c *v3Client) InstallChart(releaseName string, releaseNamespace string, values map[interface{}]interface{}, helmConfig *latest.HelmConfig) (*Release, error) {
+	if releaseNamespace == "" {
+		releaseNamespace = c.namespace
+	}
+	if helmConfig == nil || helmConfig.Chart == nil {
+		return nil, errors.New("helm.chart is required")
+	}
+
+	manifest, err := c.renderChart(releaseName, releaseNamespace, values, helmConfig.Chart)
+	if err != nil {
+		return nil, errors.Wrap(err, "render chart")
+	}
+
+	err = c.kubeClient.ApplyManifests([]byte(manifest), releaseNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "apply rendered manifest")
+	}
+
+	revision, err := c.latestRevision(releaseName, releaseNamespace)
+	if err != nil {
+		return nil, err
+	}
+	revision++
+
+	rel := &release{
+		Name:      releaseName,
+		Version:   revision,
+		Namespace: releaseNamespace,
+		Config:    convertValues(values),
+		Chart:     &chartMeta{Name: helmConfig.Chart.Name, Version: helmConfig.Chart.Version},
+		Manifest:  manifest,
+	}
+
+	encoded, err := encodeRelease(rel)
+	if err != nil {
+		return nil, errors.Wrap(err, "encode release")
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      releaseSecretName(releaseName, revision),
+			Namespace: releaseNamespace,
+			Labels: map[string]string{
+				"owner":   "helm",
+				"name":    releaseName,
+				"status":  "deployed",
+				"version": fmt.Sprintf("%d", revision),
+			},
+		},
+		Type: releaseSecretType,
+		Data: map[string][]byte{"release": []byte(encoded)},
+	}
+
+	_, err = c.kubeClient.Client.CoreV1().Secrets(releaseNamespace).Create(secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "create release secret")
+	}
+
+	return &Release{Name: releaseName, Namespace: releaseNamespace, Version: revision}, nil
+}
+
+// DeleteRelease uninstalls releaseName: the resources recorded in the latest
+// revision's manifest are always torn down, while the release secrets
+// themselves are only deleted when purge is true, matching `helm uninstall`
+// (purge=false keeps history the way `--keep-history` does)
+func (c *v3Client) DeleteRelease(releaseName string, purge bool) (*Release, error) {
+	secrets, err := c.kubeClient.Client.CoreV1().Secrets(c.namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("owner=helm,name=%s", releaseName),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "list release secrets")
+	}
+
+	latest, err := latestSecret(secrets.Items)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil {
+		rel, err := decodeRelease(string(latest.Data["release"]))
+		if err != nil {
+			return nil, errors.Wrap(err, "decode release")
+		}
+
+		if rel.Manifest != "" {
+			err = c.kubeClient.DeleteManifests([]byte(rel.Manifest), c.namespace)
+			if err != nil {
+				return nil, errors.Wrap(err, "delete rendered resources")
+			}
+		}
+	}
+
+	if purge {
+		for _, secret := range secrets.Items {
+			err = c.kubeClient.Client.CoreV1().Secrets(c.namespace).Delete(secret.Name, &metav1.DeleteOptions{})
+			if err != nil {
+				return nil, errors.Wrapf(err, "delete release secret %s", secret.Name)
+			}
+		}
+	} else {
+		for _, secret := range secrets.Items {
+			secret.Labels["status"] = "uninstalled"
+			_, err = c.kubeClient.Client.CoreV1().Secrets(c.namespace).Update(&secret)
+			if err != nil {
+				return nil, errors.Wrapf(err, "mark release secret %s uninstalled", secret.Name)
+			}
+		}
+	}
+
+	return &Release{Name: releaseName, Namespace: c.namespace}, nil
+}
+
+// latestSecret returns the secret holding the highest revision in secrets,
+// or nil if secrets is empty
+func latestSecret(secrets []corev1.Secret) (*corev1.Secret, error) {
+	var latest *corev1.Secret
+	highest := -1
+
+	for i, secret := range secrets {
+		rel, err := decodeRelease(string(secret.Data["release"]))
+		if err != nil {
+			continue
+		}
+		if rel.Version > highest {
+			highest = rel.Version
+			latest = &secrets[i]
+		}
+	}
+
+	return latest, nil
+}
+
+// renderChart loads chart.Name as a local chart (a directory or .tgz, the
+// form PullChart and a local `helm.chart.name` path both already produce)
+// and renders its templates into a single concatenated manifest, the way
+// `helm template`/`helm install` do before anything is ever applied to the
+// cluster
+func (c *v3Client) renderChart(releaseName, releaseNamespace string, values map[interface{}]interface{}, chartConfig *latest.ChartConfig) (string, error) {
+	loadedChart, err := loader.Load(chartConfig.Name)
+	if err != nil {
+		return "", errors.Wrapf(err, "load chart %s (helm v3 in devspace only installs local or oci-pulled charts, not classic repo URLs)", chartConfig.Name)
+	}
+
+	renderValues, err := chartutil.ToRenderValues(loadedChart, convertValues(values), chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: releaseNamespace,
+		IsInstall: true,
+	}, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "build render values")
+	}
+
+	rendered, err := engine.Render(loadedChart, renderValues)
+	if err != nil {
+		return "", errors.Wrap(err, "render templates")
+	}
+
+	var manifest strings.Builder
+	for name, content := range rendered {
+		if strings.TrimSpace(content) == "" || strings.HasSuffix(name, "NOTES.txt") {
+			continue
+		}
+
+		manifest.WriteString("---\n# Source: " + name + "\n")
+		manifest.WriteString(content)
+		manifest.WriteString("\n")
+	}
+
+	return manifest.String(), nil
+}
+
+// latestRevision returns the highest revision currently stored for releaseName,
+// or 0 if no release secret exists yet
+func (c *v3Client) latestRevision(releaseName, namespace string) (int, error) {
+	secrets, err := c.kubeClient.Client.CoreV1().Secrets(namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("owner=helm,name=%s", releaseName),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "list release secrets")
+	}
+
+	revisions := []int{}
+	for _, secret := range secrets.Items {
+		rel, err := decodeRelease(string(secret.Data["release"]))
+		if err != nil {
+			continue
+		}
+		revisions = append(revisions, rel.Version)
+	}
+
+	if len(revisions) == 0 {
+		return 0, nil
+	}
+
+	sort.Ints(revisions)
+	return revisions[len(revisions)-1], nil
+}
+
+func releaseSecretName(name string, revision int) string {
+	return fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, revision)
+}
+
+// encodeRelease gzips and base64-encodes a release payload, matching the
+// on-disk format helm v3 itself uses for release secrets
+func encodeRelease(rel *release) (string, error) {
+	data, err := json.Marshal(rel)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	gzWriter := gzip.NewWriter(&buf)
+	if _, err := gzWriter.Write(data); err != nil {
+		return "", err
+	}
+	if err := gzWriter.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func decodeRelease(encoded string) (*release, error) {
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	gzReader, err := gzip.NewReader(bytes.NewReader(decoded))
+	if err != nil {
+		return nil, err
+	}
+	defer gzReader.Close()
+
+	data, err := ioutil.ReadAll(gzReader)
+	if err != nil {
+		return nil, err
+	}
+
+	rel := &release{}
+	if err := json.Unmarshal(data, rel); err != nil {
+		return nil, err
+	}
+
+	return rel, nil
+}
+
+func convertValues(values map[interface{}]interface{}) map[string]interface{} {
+	converted := map[string]interface{}{}
+	for key, value := range values {
+		if strKey, ok := key.(string); ok {
+			converted[strKey] = value
+		}
+	}
+	return converted
+}
@@ -0,0 +1,146 @@
+package kubectl
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/restmapper"
+)
+
+// fieldManager identifies devspace as the writer of every resource it applies
+const fieldManager = "devspace"
+
+// ApplyManifests splits a multi-document YAML manifest (as rendered by a helm
+// chart, or read from a kubectl deployment's manifest files) and
+// server-side-applies each document, creating resources that don't exist yet
+// and patching ones that do
+func (client *Client) ApplyManifests(manifests []byte, defaultNamespace string) error {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+
+	for {
+		obj := &unstructured.Unstructured{}
+		err := decoder.Decode(obj)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return errors.Wrap(err, "decode manifest")
+		}
+
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		err = client.applyObject(obj, defaultNamespace)
+		if err != nil {
+			return errors.Wrapf(err, "apply %s/%s", obj.GetKind(), obj.GetName())
+		}
+	}
+
+	return nil
+}
+
+func (client *Client) applyObject(obj *unstructured.Unstructured, defaultNamespace string) error {
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	gvr, namespaced, err := client.resourceFor(obj.GroupVersionKind())
+	if err != nil {
+		return err
+	}
+
+	resourceClient := client.Dynamic.Resource(gvr)
+	if namespaced {
+		resourceClient = client.Dynamic.Resource(gvr).Namespace(namespace)
+	}
+
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = resourceClient.Patch(obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: fieldManager})
+	if err != nil && apierrors.IsNotFound(err) {
+		_, err = resourceClient.Create(obj, metav1.CreateOptions{FieldManager: fieldManager})
+	}
+
+	return err
+}
+
+// DeleteManifests splits a multi-document YAML manifest and deletes every
+// resource it describes, ignoring ones that are already gone
+func (client *Client) DeleteManifests(manifests []byte, defaultNamespace string) error {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+
+	for {
+		obj := &unstructured.Unstructured{}
+		err := decoder.Decode(obj)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return errors.Wrap(err, "decode manifest")
+		}
+
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		err = client.deleteObject(obj, defaultNamespace)
+		if err != nil {
+			return errors.Wrapf(err, "delete %s/%s", obj.GetKind(), obj.GetName())
+		}
+	}
+
+	return nil
+}
+
+func (client *Client) deleteObject(obj *unstructured.Unstructured, defaultNamespace string) error {
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	gvr, namespaced, err := client.resourceFor(obj.GroupVersionKind())
+	if err != nil {
+		return err
+	}
+
+	resourceClient := client.Dynamic.Resource(gvr)
+	if namespaced {
+		resourceClient = client.Dynamic.Resource(gvr).Namespace(namespace)
+	}
+
+	err = resourceClient.Delete(obj.GetName(), &metav1.DeleteOptions{})
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// resourceFor resolves the GroupVersionResource and whether it is namespaced
+// for gvk, using the cluster's discovered API resources
+func (client *Client) resourceFor(gvk schema.GroupVersionKind) (schema.GroupVersionResource, bool, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(client.Discovery)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, err
+	}
+
+	return mapping.Resource, mapping.Scope.Name() == "namespace", nil
+}
@@ -0,0 +1,70 @@
+package kubectl
+
+import (
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Client bundles the kubernetes clientset together with the context it was
+// created for
+type Client struct {
+	Client     kubernetes.Interface
+	RestConfig *rest.Config
+
+	// Dynamic and Discovery back ApplyManifests, so callers that only render
+	// YAML (e.g. the helm v3 client) can apply it without knowing every
+	// resource's Go type up front
+	Dynamic   dynamic.Interface
+	Discovery discovery.DiscoveryInterface
+
+	CurrentContext string
+	Namespace      string
+}
+
+// NewClientFromGetter builds a Client the same way kubectl and helm resolve
+// cluster access: via a genericclioptions.RESTClientGetter, which already
+// merges $KUBECONFIG, --kubeconfig, --context, --namespace and
+// --as/--as-group according to client-go's standard loading rules
+func NewClientFromGetter(getter genericclioptions.RESTClientGetter) (*Client, error) {
+	restConfig, err := getter.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	rawConfig, err := getter.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, _, err := getter.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		Client:         clientset,
+		RestConfig:     restConfig,
+		Dynamic:        dynamicClient,
+		Discovery:      discoveryClient,
+		CurrentContext: rawConfig.CurrentContext,
+		Namespace:      namespace,
+	}, nil
+}
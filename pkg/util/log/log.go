@@ -0,0 +1,39 @@
+package log
+
+import "fmt"
+
+// Logger defines the common logging interface used throughout devspace
+type Logger interface {
+	Info(args ...interface{})
+	Infof(format string, args ...interface{})
+	Done(args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// Discard is a logger that swallows all output
+var Discard Logger = &discardLogger{}
+
+var defaultLogger Logger = &stdLogger{}
+
+// GetInstance returns the default logger instance
+func GetInstance() Logger {
+	return defaultLogger
+}
+
+type discardLogger struct{}
+
+func (d *discardLogger) Info(args ...interface{})                 {}
+func (d *discardLogger) Infof(format string, args ...interface{}) {}
+func (d *discardLogger) Done(args ...interface{})                 {}
+func (d *discardLogger) Errorf(format string, args ...interface{}) {}
+
+type stdLogger struct{}
+
+func (s *stdLogger) Info(args ...interface{})  { fmt.Println(args...) }
+func (s *stdLogger) Done(args ...interface{})  { fmt.Println(args...) }
+func (s *stdLogger) Infof(format string, args ...interface{}) {
+	fmt.Printf(format+"\n", args...)
+}
+func (s *stdLogger) Errorf(format string, args ...interface{}) {
+	fmt.Printf(format+"\n", args...)
+}
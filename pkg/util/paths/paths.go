@@ -0,0 +1,95 @@
+// Package paths resolves where devspace keeps its home-level state
+// (persistent config, cache, plugin binaries), honoring the XDG Base
+// Directory spec while staying compatible with installs that still have a
+// legacy ~/.devspace directory.
+package paths
+
+import (
+	"os"
+	"path/filepath"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// appName is the subdirectory devspace keeps its state in under each XDG
+// base directory
+const appName = "devspace"
+
+// HomeDir returns the current user's home directory. It exists so callers
+// that only need $HOME (not one of the XDG directories below) still go
+// through this package instead of importing go-homedir directly.
+func HomeDir() (string, error) {
+	return homedir.Dir()
+}
+
+// LegacyDir returns the pre-XDG ~/.devspace directory
+func LegacyDir() (string, error) {
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".devspace"), nil
+}
+
+// legacyExists reports whether ~/.devspace is already present, in which case
+// every XDG directory below falls back to it for backwards compatibility
+func legacyExists() bool {
+	legacy, err := LegacyDir()
+	if err != nil {
+		return false
+	}
+
+	info, err := os.Stat(legacy)
+	return err == nil && info.IsDir()
+}
+
+// ConfigDir returns the directory devspace stores persistent home-level
+// config in (plugin manifests, kubecontext preferences): $XDG_CONFIG_HOME/devspace,
+// defaulting to ~/.config/devspace, or ~/.devspace if that already exists
+func ConfigDir() (string, error) {
+	if legacyExists() {
+		return LegacyDir()
+	}
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// CacheDir returns the directory devspace caches pulled charts and image
+// metadata in: $XDG_CACHE_HOME/devspace, defaulting to ~/.cache/devspace, or
+// ~/.devspace/cache if the legacy directory already exists
+func CacheDir() (string, error) {
+	if legacyExists() {
+		legacy, err := LegacyDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(legacy, "cache"), nil
+	}
+	return xdgDir("XDG_CACHE_HOME", ".cache")
+}
+
+// DataDir returns the directory devspace stores plugin binaries in:
+// $XDG_DATA_HOME/devspace, defaulting to ~/.local/share/devspace, or
+// ~/.devspace/plugins' parent if the legacy directory already exists
+func DataDir() (string, error) {
+	if legacyExists() {
+		return LegacyDir()
+	}
+	return xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+}
+
+// xdgDir resolves $<envVar>/devspace, falling back to ~/<defaultRelHome>/devspace
+// when envVar isn't set, and ensures the directory exists
+func xdgDir(envVar, defaultRelHome string) (string, error) {
+	base := os.Getenv(envVar)
+	if base == "" {
+		home, err := homedir.Dir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, defaultRelHome)
+	}
+
+	dir := filepath.Join(base, appName)
+	return dir, os.MkdirAll(dir, 0755)
+}